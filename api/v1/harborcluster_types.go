@@ -0,0 +1,92 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ComponentKind selects whether a Harbor dependency (Redis, database,
+// storage, ...) is deployed in-cluster by the operator or points at an
+// existing external service.
+type ComponentKind string
+
+const (
+	ExternalComponent  ComponentKind = "external"
+	InClusterComponent ComponentKind = "inCluster"
+)
+
+// HarborCluster is the Schema for the harborclusters API.
+type HarborCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HarborClusterSpec   `json:"spec,omitempty"`
+	Status HarborClusterStatus `json:"status,omitempty"`
+}
+
+// HarborClusterSpec defines the desired state of HarborCluster.
+type HarborClusterSpec struct {
+	Redis RedisComponent `json:"redis,omitempty"`
+}
+
+// RedisComponent selects whether Redis is deployed in-cluster or external,
+// and carries the configuration for whichever kind is chosen.
+type RedisComponent struct {
+	Kind ComponentKind `json:"kind,omitempty"`
+	Spec *RedisSpec    `json:"spec,omitempty"`
+}
+
+// RedisHostSpec is a single Redis endpoint. Port travels with its host so
+// cluster-mode nodes that each listen on a different port can be
+// expressed.
+type RedisHostSpec struct {
+	Host string `json:"host"`
+	Port string `json:"port"`
+}
+
+// RedisSpec configures an external Redis deployment.
+type RedisSpec struct {
+	Schema     string          `json:"schema,omitempty"`
+	Hosts      []RedisHostSpec `json:"hosts,omitempty"`
+	GroupName  string          `json:"groupName,omitempty"`
+	SecretName string          `json:"secretName,omitempty"`
+
+	// RouteByLatency and ReadOnly only apply when Schema is "cluster"; they
+	// mirror the equivalent go-redis ClusterOptions and let reads be spread
+	// across replicas.
+	RouteByLatency bool `json:"routeByLatency,omitempty"`
+	ReadOnly       bool `json:"readOnly,omitempty"`
+
+	// Components assigns each Harbor component (keyed by name, e.g.
+	// "chartMuseum") its own logical database index or key prefix, so they
+	// can share one external Redis deployment without colliding.
+	Components map[string]RedisComponentSpec `json:"components,omitempty"`
+
+	// TLS enables encryption-in-transit for the Redis connection.
+	TLS *RedisTLSSpec `json:"tls,omitempty"`
+
+	// Vault, when set, sources the Redis password from HashiCorp Vault
+	// instead of SecretName.
+	Vault *VaultRef `json:"vault,omitempty"`
+
+	// AWSSecretsManager, when set, sources the Redis password from AWS
+	// Secrets Manager instead of SecretName.
+	AWSSecretsManager *AWSSecretsManagerRef `json:"awsSecretsManager,omitempty"`
+}
+
+// HarborClusterStatus defines the observed state of HarborCluster.
+type HarborClusterStatus struct {
+	Conditions []HarborClusterCondition `json:"conditions,omitempty"`
+}
+
+// ConditionType is the type of a HarborClusterCondition.
+type ConditionType string
+
+// HarborClusterCondition describes the state of a HarborCluster component
+// at a point in time.
+type HarborClusterCondition struct {
+	Type               ConditionType          `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
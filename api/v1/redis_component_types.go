@@ -0,0 +1,26 @@
+package v1
+
+// RedisComponentSpec lets operators assign a Harbor component a dedicated
+// Redis logical database index or key prefix, so multiple components
+// sharing one external Redis deployment don't collide with each other's
+// keys.
+type RedisComponentSpec struct {
+	// DB is the Redis logical database index (0-15) to use. Leave nil to
+	// use the default database. Not honored in cluster mode, where Redis
+	// only ever exposes database 0.
+	DB *int `json:"db,omitempty"`
+
+	// KeyPrefix namespaces every key this component writes, as an
+	// alternative to a dedicated DB index.
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+}
+
+// GetComponentSpec returns the RedisComponentSpec configured for the named
+// Harbor component, or the zero value (default database, no prefix) if the
+// operator didn't configure one.
+func (spec *RedisSpec) GetComponentSpec(component string) RedisComponentSpec {
+	if spec.Components == nil {
+		return RedisComponentSpec{}
+	}
+	return spec.Components[component]
+}
@@ -0,0 +1,21 @@
+package v1
+
+// VaultRef addresses a Redis password stored as a HashiCorp Vault KV v2
+// secret.
+type VaultRef struct {
+	Address string `json:"address"`
+	Path    string `json:"path"`
+
+	// AuthMethod selects how the operator authenticates to Vault. Only
+	// "kubernetes" is supported.
+	AuthMethod string `json:"authMethod"`
+	RoleName   string `json:"roleName,omitempty"`
+}
+
+// AWSSecretsManagerRef addresses a Redis password stored in AWS Secrets
+// Manager. The operator authenticates via IRSA rather than static
+// credentials.
+type AWSSecretsManagerRef struct {
+	SecretId string `json:"secretId"`
+	Region   string `json:"region"`
+}
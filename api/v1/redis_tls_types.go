@@ -0,0 +1,12 @@
+package v1
+
+// RedisTLSSpec configures encryption-in-transit for the Redis connection.
+// SecretName references a Kubernetes Secret carrying ca.crt and, for
+// mutual TLS, tls.crt/tls.key as well.
+type RedisTLSSpec struct {
+	SecretName string `json:"secretName"`
+
+	// InsecureSkipVerify disables server certificate verification. Only
+	// meant for development; regulated deployments should leave it false.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
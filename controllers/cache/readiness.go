@@ -3,6 +3,9 @@ package cache
 import (
 	"errors"
 	"fmt"
+	"net"
+	"reflect"
+
 	rediscli "github.com/go-redis/redis"
 	goharborv1 "github.com/goharbor/harbor-cluster-operator/api/v1"
 	"github.com/goharbor/harbor-cluster-operator/lcm"
@@ -36,7 +39,7 @@ var (
 // - return redis properties if redis has available
 func (redis *RedisReconciler) Readiness() (*lcm.CRStatus, error) {
 	var (
-		client *rediscli.Client
+		client RedisClient
 		err    error
 	)
 
@@ -58,15 +61,49 @@ func (redis *RedisReconciler) Readiness() (*lcm.CRStatus, error) {
 	if err := client.Ping().Err(); err != nil {
 		redis.Log.Error(err, "Fail to check Redis.",
 			"namespace", redis.HarborCluster.Namespace, "name", redis.HarborCluster.Name)
+		if isTLSHandshakeError(err) {
+			return cacheNotReadyStatus(RedisTLSHandshakeError, err.Error()), err
+		}
 		return cacheNotReadyStatus(CheckRedisHealthError, err.Error()), err
 	}
 
+	if clusterClient, ok := client.(*rediscli.ClusterClient); ok {
+		if err := ClusterShardsHealthy(clusterClient); err != nil {
+			redis.Log.Error(err, "Fail to check Redis Cluster shards.",
+				"namespace", redis.HarborCluster.Namespace, "name", redis.HarborCluster.Name)
+			return cacheNotReadyStatus(CheckRedisHealthError, err.Error()), err
+		}
+	}
+
+	checker := redis.shardHealthChecker()
+
+	for _, transition := range checker.DrainTransitions() {
+		redis.emitShardTransitionEvent(transition.Endpoint, transition.Healthy, transition.Message)
+	}
+
+	redis.syncShardConditions(checker)
+
+	if endpoints := redis.RedisConnect.shardEndpoints(); !checker.Ready(shardQuorum(len(endpoints))) {
+		err := fmt.Errorf("waiting for a quorum of redis shards to settle, have %d endpoints", len(endpoints))
+		redis.Log.Error(err, "Redis shards have not settled yet.",
+			"namespace", redis.HarborCluster.Namespace, "name", redis.HarborCluster.Name)
+		return cacheNotReadyStatus(ShardQuorumNotReadyError, err.Error()), err
+	}
+
 	redis.Log.Info("Redis already ready.",
 		"namespace", redis.HarborCluster.Namespace, "name", redis.HarborCluster.Name)
 
 	properties := lcm.Properties{}
 	for _, component := range components {
-		url := redis.RedisConnect.GenRedisConnURL()
+		componentSpec := redis.HarborCluster.Spec.Redis.Spec.GetComponentSpec(component)
+
+		if err := redis.ValidateComponentDatabase(client, componentSpec); err != nil {
+			redis.Log.Error(err, "Fail to validate component Redis database.",
+				"namespace", redis.HarborCluster.Namespace, "name", redis.HarborCluster.Name, "component", component)
+			return cacheNotReadyStatus(InvalidComponentDatabaseError, err.Error()), err
+		}
+
+		url := redis.RedisConnect.GenRedisComponentConnURL(componentSpec)
 		secretName := fmt.Sprintf("%s-redis", strings.ToLower(component))
 		propertyName := fmt.Sprintf("%sSecret", component)
 
@@ -102,27 +139,51 @@ func (redis *RedisReconciler) DeployComponentSecret(component, url, namespace, s
 	}
 
 	err := redis.Client.Get(types.NamespacedName{Name: secretName, Namespace: redis.HarborCluster.Namespace}, secret)
-	if err != nil && kerr.IsNotFound(err) {
-		redis.Log.Info("Creating Harbor Component Secret",
-			"namespace", redis.HarborCluster.Namespace,
-			"name", secretName,
-			"component", component)
-		return redis.Client.Create(sc)
+	if err != nil {
+		if kerr.IsNotFound(err) {
+			redis.Log.Info("Creating Harbor Component Secret",
+				"namespace", redis.HarborCluster.Namespace,
+				"name", secretName,
+				"component", component)
+			return redis.Client.Create(sc)
+		}
+		return err
 	}
 
-	return err
+	if reflect.DeepEqual(secret.Data, sc.Data) {
+		return nil
+	}
+
+	// The password provider rotated (or the URL otherwise changed) since
+	// this secret was last written: update it in place so chartmuseum,
+	// clair, jobservice and registry actually pick up the new value
+	// instead of keeping the stale one forever.
+	redis.Log.Info("Updating Harbor Component Secret",
+		"namespace", redis.HarborCluster.Namespace,
+		"name", secretName,
+		"component", component)
+
+	sc.ResourceVersion = secret.ResourceVersion
+
+	return redis.Client.Update(sc)
 }
 
-func (redis *RedisReconciler) GetExternalRedisInfo() (*rediscli.Client, error) {
+func (redis *RedisReconciler) GetExternalRedisInfo() (RedisClient, error) {
 	var (
 		connect  *RedisConnect
 		endpoint []string
 		port     string
-		client   *rediscli.Client
+		client   RedisClient
 		err      error
 		pw       string
 	)
 	spec := redis.HarborCluster.Spec.Redis.Spec
+
+	tlsConfig, err := redis.buildTLSConfig(spec.TLS)
+	if err != nil {
+		return nil, err
+	}
+
 	switch spec.Schema {
 	case RedisSentinelSchema:
 		if len(spec.Hosts) < 1 || spec.GroupName == "" {
@@ -141,6 +202,7 @@ func (redis *RedisReconciler) GetExternalRedisInfo() (*rediscli.Client, error) {
 			Password:  pw,
 			GroupName: spec.GroupName,
 			Schema:    RedisSentinelSchema,
+			TLSConfig: tlsConfig,
 		}
 
 		redis.RedisConnect = connect
@@ -161,9 +223,29 @@ func (redis *RedisReconciler) GetExternalRedisInfo() (*rediscli.Client, error) {
 			Password:  pw,
 			GroupName: spec.GroupName,
 			Schema:    RedisServerSchema,
+			TLSConfig: tlsConfig,
 		}
 		redis.RedisConnect = connect
 		client = connect.NewRedisClient()
+	case RedisClusterSchema:
+		if len(spec.Hosts) < 1 {
+			return nil, errors.New(".redis.spec.hosts is invalid")
+		}
+
+		if spec.SecretName != "" || spec.Vault != nil || spec.AWSSecretsManager != nil {
+			pw, err = redis.GetExternalRedisPassword(spec)
+		}
+
+		connect = &RedisConnect{
+			Addrs:          GetExternalRedisClusterAddrs(spec),
+			Password:       pw,
+			RouteByLatency: spec.RouteByLatency,
+			ReadOnly:       spec.ReadOnly,
+			Schema:         RedisClusterSchema,
+			TLSConfig:      tlsConfig,
+		}
+		redis.RedisConnect = connect
+		client = connect.NewRedisClusterClient()
 	}
 
 	if err != nil {
@@ -187,20 +269,32 @@ func GetExternalRedisHost(spec *goharborv1.RedisSpec) ([]string, string) {
 	return endpoint, port
 }
 
-// GetExternalRedisPassword returns external redis password
-func (redis *RedisReconciler) GetExternalRedisPassword(spec *goharborv1.RedisSpec) (string, error) {
-
-	pw, err := redis.GetRedisPassword(spec.SecretName)
-	if err != nil {
-		return "", err
+// GetExternalRedisClusterAddrs returns the "host:port" address list used to
+// seed a Redis Cluster client. Unlike sentinel/single-server mode, each
+// cluster node can be reached on its own port, so the port travels with its
+// host instead of being hoisted to a single shared value.
+func GetExternalRedisClusterAddrs(spec *goharborv1.RedisSpec) []string {
+	var addrs []string
+	for _, host := range spec.Hosts {
+		addrs = append(addrs, net.JoinHostPort(host.Host, host.Port))
 	}
+	return addrs
+}
 
-	return pw, err
+// GetExternalRedisPassword returns external redis password, resolved
+// through whichever PasswordProvider spec is configured with.
+func (redis *RedisReconciler) GetExternalRedisPassword(spec *goharborv1.RedisSpec) (string, error) {
+	return redis.resolveRedisPassword(spec)
 }
 
 // GetInClusterRedisInfo returns inCluster redis sentinel pool client
-func (redis *RedisReconciler) GetInClusterRedisInfo() (*rediscli.Client, error) {
-	password, err := redis.GetRedisPassword(redis.HarborCluster.Name)
+func (redis *RedisReconciler) GetInClusterRedisInfo() (RedisClient, error) {
+	// In-cluster Redis is operator-managed, so its password always lives in
+	// the Kubernetes Secret the operator itself created, regardless of which
+	// PasswordProvider external Redis is configured with.
+	provider := &KubernetesSecretProvider{redis: redis, secretName: redis.HarborCluster.Name}
+
+	password, _, err := provider.GetPassword()
 	if err != nil {
 		return nil, err
 	}
@@ -232,11 +326,17 @@ func (redis *RedisReconciler) GetInClusterRedisInfo() (*rediscli.Client, error)
 
 	endpoint := redis.GetSentinelServiceUrl(currentSentinelPods)
 
+	tlsConfig, err := redis.buildTLSConfig(redis.HarborCluster.Spec.Redis.Spec.TLS)
+	if err != nil {
+		return nil, err
+	}
+
 	connect := &RedisConnect{
 		Endpoints: []string{endpoint},
 		Port:      RedisSentinelConnPort,
 		Password:  password,
 		GroupName: RedisSentinelConnGroup,
+		TLSConfig: tlsConfig,
 	}
 
 	redis.RedisConnect = connect
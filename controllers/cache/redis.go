@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	rediscli "github.com/go-redis/redis"
+)
+
+const (
+	RedisSentinelSchema = "sentinel"
+	RedisServerSchema   = "server"
+
+	RedisSentinelConnPort  = "26379"
+	RedisSentinelConnGroup = "mymaster"
+)
+
+// RedisConnect holds the resolved connection parameters used to build a
+// Redis client and its connection URL, regardless of deployment topology
+// (single server, sentinel or cluster).
+type RedisConnect struct {
+	// Endpoints/Port address a single server or a sentinel group.
+	Endpoints []string
+	Port      string
+
+	// Addrs is cluster mode only: one "host:port" entry per cluster node.
+	Addrs []string
+
+	Password  string
+	GroupName string
+	Schema    string
+
+	// ReadOnly and RouteByLatency only apply in cluster mode.
+	ReadOnly       bool
+	RouteByLatency bool
+
+	TLSConfig *tls.Config
+}
+
+// NewRedisPool builds a sentinel-backed failover client.
+func (connect *RedisConnect) NewRedisPool() *rediscli.Client {
+	return rediscli.NewFailoverClient(&rediscli.FailoverOptions{
+		MasterName:    connect.GroupName,
+		SentinelAddrs: connect.sentinelAddrs(),
+		Password:      connect.Password,
+		TLSConfig:     connect.TLSConfig,
+	})
+}
+
+// NewRedisClient builds a single-server client.
+func (connect *RedisConnect) NewRedisClient() *rediscli.Client {
+	return rediscli.NewClient(&rediscli.Options{
+		Addr:      net.JoinHostPort(connect.Endpoints[0], connect.Port),
+		Password:  connect.Password,
+		TLSConfig: connect.TLSConfig,
+	})
+}
+
+// sentinelAddrs returns one "host:port" entry per discovered sentinel.
+func (connect *RedisConnect) sentinelAddrs() []string {
+	var addrs []string
+	for _, host := range connect.Endpoints {
+		addrs = append(addrs, net.JoinHostPort(host, connect.Port))
+	}
+	return addrs
+}
+
+// GenRedisConnURL renders the connection URL Harbor components are handed,
+// switching scheme by topology: redis:// for a single server,
+// redis+sentinel:// for sentinel, redis+cluster:// for cluster mode.
+func (connect *RedisConnect) GenRedisConnURL() string {
+	var scheme, hosts string
+
+	switch connect.Schema {
+	case RedisSentinelSchema:
+		scheme = "redis+sentinel"
+		hosts = strings.Join(connect.sentinelAddrs(), ",")
+	case RedisClusterSchema:
+		scheme = "redis+cluster"
+		hosts = strings.Join(connect.Addrs, ",")
+	default:
+		scheme = "redis"
+		hosts = net.JoinHostPort(connect.Endpoints[0], connect.Port)
+	}
+
+	auth := ""
+	if connect.Password != "" {
+		auth = fmt.Sprintf("user:%s@", url.QueryEscape(connect.Password))
+	}
+
+	connURL := fmt.Sprintf("%s://%s%s", scheme, auth, hosts)
+
+	if connect.Schema == RedisSentinelSchema && connect.GroupName != "" {
+		connURL += "?master=" + url.QueryEscape(connect.GroupName)
+	}
+
+	return connURL
+}
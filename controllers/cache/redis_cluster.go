@@ -0,0 +1,45 @@
+package cache
+
+import (
+	rediscli "github.com/go-redis/redis"
+)
+
+// RedisClusterSchema configures the Redis Cluster mode of RedisSpec.Schema,
+// alongside RedisSentinelSchema and RedisServerSchema.
+const RedisClusterSchema = "cluster"
+
+// RedisClient is the subset of Redis command coverage the reconciler
+// depends on. Both a sentinel/single-server *rediscli.Client and a
+// *rediscli.ClusterClient satisfy it, so Readiness and
+// ValidateComponentDatabase can stay agnostic to the underlying Redis
+// topology.
+type RedisClient interface {
+	rediscli.Cmdable
+	Close() error
+}
+
+// NewRedisClusterClient builds a Redis Cluster client from the resolved
+// connect info. ReadOnly and RouteByLatency mirror the corresponding
+// go-redis ClusterOptions, letting operators spread reads across replicas.
+func (connect *RedisConnect) NewRedisClusterClient() *rediscli.ClusterClient {
+	return rediscli.NewClusterClient(&rediscli.ClusterOptions{
+		Addrs:          connect.Addrs,
+		Password:       connect.Password,
+		ReadOnly:       connect.ReadOnly,
+		RouteByLatency: connect.RouteByLatency,
+		TLSConfig:      connect.TLSConfig,
+	})
+}
+
+// ClusterShardsHealthy pings every master and replica known to the cluster
+// client and returns the first error encountered, so callers can tell a
+// single degraded shard apart from the whole cluster being unreachable.
+func ClusterShardsHealthy(client *rediscli.ClusterClient) error {
+	if _, err := client.ClusterInfo().Result(); err != nil {
+		return err
+	}
+
+	return client.ForEachNode(func(shard *rediscli.Client) error {
+		return shard.Ping().Err()
+	})
+}
@@ -0,0 +1,20 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	rediscli "github.com/go-redis/redis"
+)
+
+func TestClusterShardsHealthyPropagatesClusterInfoError(t *testing.T) {
+	client := rediscli.NewClusterClient(&rediscli.ClusterOptions{
+		Addrs:       []string{"127.0.0.1:1"},
+		DialTimeout: 50 * time.Millisecond,
+	})
+	defer client.Close()
+
+	if err := ClusterShardsHealthy(client); err == nil {
+		t.Fatal("ClusterShardsHealthy() = nil, want error when no shard is reachable")
+	}
+}
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"fmt"
+	"net/url"
+
+	rediscli "github.com/go-redis/redis"
+	goharborv1 "github.com/goharbor/harbor-cluster-operator/api/v1"
+)
+
+// InvalidComponentDatabaseError is returned via cacheNotReadyStatus when a
+// component's configured RedisComponentSpec.DB does not exist on the target
+// Redis deployment.
+const InvalidComponentDatabaseError = "InvalidComponentDatabaseError"
+
+// ValidateComponentDatabase confirms the logical database index assigned to
+// a Harbor component actually exists on the target Redis deployment before
+// a secret pointing at it is handed out. In cluster mode Redis only ever
+// exposes DB 0, so isolation there is key-prefix only and this degrades to
+// a cluster health check. A mismatched DB index would otherwise only show
+// up much later as a confusing "wrong data" bug inside the component.
+func (redis *RedisReconciler) ValidateComponentDatabase(client RedisClient, spec goharborv1.RedisComponentSpec) error {
+	if spec.DB == nil {
+		return nil
+	}
+
+	if clusterClient, ok := client.(*rediscli.ClusterClient); ok {
+		if _, err := clusterClient.ClusterInfo().Result(); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	reply, err := client.ConfigGet("databases").Result()
+	if err != nil {
+		return err
+	}
+
+	count, err := parseConfigGetInt(reply)
+	if err != nil {
+		return err
+	}
+
+	if *spec.DB < 0 || *spec.DB >= count {
+		return fmt.Errorf("redis database %d is out of range, server reports %d databases", *spec.DB, count)
+	}
+
+	return nil
+}
+
+// parseConfigGetInt extracts the integer value out of a Redis CONFIG GET
+// reply, which comes back as a flat [name, value] slice.
+func parseConfigGetInt(reply []interface{}) (int, error) {
+	if len(reply) != 2 {
+		return 0, fmt.Errorf("unexpected CONFIG GET reply: %v", reply)
+	}
+
+	value, ok := reply[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected CONFIG GET value type: %T", reply[1])
+	}
+
+	var count int
+	if _, err := fmt.Sscanf(value, "%d", &count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GenRedisComponentConnURL builds the per-component connection URL, applying
+// the component's dedicated DB index or key prefix on top of the shared
+// connect info so components sharing one external Redis deployment don't
+// collide with each other's keys. It parses the shared URL instead of
+// concatenating onto it, so a query string GenRedisConnURL already set
+// (e.g. sentinel's "?master=...") is merged with rather than duplicated by
+// the key-prefix param.
+func (connect *RedisConnect) GenRedisComponentConnURL(spec goharborv1.RedisComponentSpec) string {
+	raw := connect.GenRedisConnURL()
+	if connect.TLSConfig != nil {
+		raw = upgradeRedisURLScheme(raw)
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	if spec.DB != nil {
+		parsed.Path = fmt.Sprintf("/%d", *spec.DB)
+	}
+
+	if spec.KeyPrefix != "" {
+		query := parsed.Query()
+		query.Set("prefix", spec.KeyPrefix)
+		parsed.RawQuery = query.Encode()
+	}
+
+	return parsed.String()
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-cluster-operator/api/v1"
+)
+
+func TestGenRedisComponentConnURL(t *testing.T) {
+	db := 2
+
+	cases := []struct {
+		name string
+		spec goharborv1.RedisComponentSpec
+		want string
+	}{
+		{
+			name: "no override",
+			spec: goharborv1.RedisComponentSpec{},
+			want: "redis+sentinel://s1:26379?master=mymaster",
+		},
+		{
+			name: "db index",
+			spec: goharborv1.RedisComponentSpec{DB: &db},
+			want: "redis+sentinel://s1:26379/2?master=mymaster",
+		},
+		{
+			name: "key prefix",
+			spec: goharborv1.RedisComponentSpec{KeyPrefix: "clair:"},
+			want: "redis+sentinel://s1:26379?master=mymaster&prefix=clair%3A",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			connect := &RedisConnect{
+				Endpoints: []string{"s1"},
+				Port:      "26379",
+				GroupName: "mymaster",
+				Schema:    RedisSentinelSchema,
+			}
+
+			if got := connect.GenRedisComponentConnURL(tc.spec); got != tc.want {
+				t.Errorf("GenRedisComponentConnURL() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetComponentSpecDefaultsToZeroValue(t *testing.T) {
+	spec := &goharborv1.RedisSpec{}
+
+	if got := spec.GetComponentSpec(HarborClair); got != (goharborv1.RedisComponentSpec{}) {
+		t.Errorf("GetComponentSpec() = %+v, want zero value", got)
+	}
+}
+
+func TestParseConfigGetInt(t *testing.T) {
+	count, err := parseConfigGetInt([]interface{}{"databases", "16"})
+	if err != nil {
+		t.Fatalf("parseConfigGetInt() error = %v", err)
+	}
+	if count != 16 {
+		t.Errorf("parseConfigGetInt() = %d, want 16", count)
+	}
+
+	if _, err := parseConfigGetInt([]interface{}{"databases"}); err == nil {
+		t.Error("parseConfigGetInt() with malformed reply, want error")
+	}
+}
@@ -0,0 +1,393 @@
+package cache
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	rediscli "github.com/go-redis/redis"
+	goharborv1 "github.com/goharbor/harbor-cluster-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultShardSettleWindow   = 30 * time.Second
+	shardDiscoveryBackoff      = 100 * time.Millisecond
+
+	shardConditionPrefix = "RedisShard/"
+)
+
+// ShardQuorumNotReadyError is returned via cacheNotReadyStatus while waiting
+// for a quorum of Redis shards to settle past defaultShardSettleWindow.
+const ShardQuorumNotReadyError = "ShardQuorumNotReadyError"
+
+// ShardStatus captures the last observed health of a single Redis endpoint
+// along with when it last flipped between healthy and unhealthy.
+type ShardStatus struct {
+	Endpoint       string
+	Healthy        bool
+	LastTransition time.Time
+	Message        string
+}
+
+// shardTransition is queued by record whenever a shard flips state, so the
+// event it causes can be emitted later by a live reconcile with a fresh
+// HarborCluster and event recorder, rather than by the background goroutine.
+type shardTransition struct {
+	Endpoint string
+	Healthy  bool
+	Message  string
+}
+
+// ShardHealthChecker polls every discovered Redis endpoint on Interval and
+// keeps a per-endpoint ShardStatus, so a single flaky shard no longer fails
+// the whole reconcile. Readiness only reports the cache ready once a
+// quorum of endpoints has been continuously healthy for at least Settle.
+//
+// A checker outlives any single RedisReconciler: controller-runtime
+// recreates the reconciler on every reconcile, so the connect info the
+// background poller uses is refreshed out-of-band via Refresh instead of
+// being captured once when the goroutine starts.
+type ShardHealthChecker struct {
+	Interval time.Duration
+	Settle   time.Duration
+
+	connect atomic.Value // holds *RedisConnect
+
+	mu          sync.Mutex
+	statuses    map[string]*ShardStatus
+	transitions []shardTransition
+
+	stop chan struct{}
+}
+
+// NewShardHealthChecker builds a checker using the repo's default polling
+// cadence and settle window.
+func NewShardHealthChecker() *ShardHealthChecker {
+	return &ShardHealthChecker{
+		Interval: defaultHealthCheckInterval,
+		Settle:   defaultShardSettleWindow,
+		statuses: make(map[string]*ShardStatus),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Refresh updates the connection info the background poller uses. Call it
+// every reconcile so a rotated password, new TLS config or changed
+// endpoint list takes effect on the checker's very next tick instead of
+// being silently ignored for the process lifetime.
+func (checker *ShardHealthChecker) Refresh(connect *RedisConnect) {
+	checker.connect.Store(connect)
+}
+
+func (checker *ShardHealthChecker) currentConnect() *RedisConnect {
+	connect, _ := checker.connect.Load().(*RedisConnect)
+	return connect
+}
+
+// Start runs the polling loop until Stop is called. It is meant to be
+// launched with `go checker.Start()` once per monitored HarborCluster,
+// after at least one call to Refresh.
+func (checker *ShardHealthChecker) Start() {
+	// Give newly discovered endpoints a moment to come up before the first
+	// poll, mirroring the short-sleep pattern already used while connecting
+	// to a sentinel failover during shard discovery.
+	time.Sleep(shardDiscoveryBackoff)
+
+	ticker := time.NewTicker(checker.Interval)
+	defer ticker.Stop()
+
+	checker.pollOnce()
+
+	for {
+		select {
+		case <-ticker.C:
+			checker.pollOnce()
+		case <-checker.stop:
+			return
+		}
+	}
+}
+
+// Stop terminates the polling loop started by Start.
+func (checker *ShardHealthChecker) Stop() {
+	close(checker.stop)
+}
+
+func (checker *ShardHealthChecker) pollOnce() {
+	connect := checker.currentConnect()
+	if connect == nil {
+		return
+	}
+
+	endpoints := connect.shardEndpoints()
+	checker.pruneStatuses(endpoints)
+
+	for _, endpoint := range endpoints {
+		healthy, message := probeShard(connect, endpoint)
+		checker.record(endpoint, healthy, message)
+	}
+}
+
+// probeShard issues PING, INFO replication and, in sentinel mode, SENTINEL
+// master <name> against a single endpoint.
+func probeShard(connect *RedisConnect, endpoint string) (bool, string) {
+	client := rediscli.NewClient(&rediscli.Options{
+		Addr:      endpoint,
+		Password:  connect.Password,
+		TLSConfig: connect.TLSConfig,
+	})
+	defer client.Close()
+
+	if err := client.Ping().Err(); err != nil {
+		return false, err.Error()
+	}
+
+	if err := client.Info("replication").Err(); err != nil {
+		return false, err.Error()
+	}
+
+	if connect.Schema == RedisSentinelSchema {
+		if err := client.Do("SENTINEL", "master", connect.GroupName).Err(); err != nil {
+			return false, err.Error()
+		}
+	}
+
+	return true, ""
+}
+
+func (checker *ShardHealthChecker) record(endpoint string, healthy bool, message string) {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	status, known := checker.statuses[endpoint]
+	if !known {
+		status = &ShardStatus{Endpoint: endpoint, LastTransition: time.Now()}
+		checker.statuses[endpoint] = status
+	}
+
+	transitioned := status.Healthy != healthy
+	if transitioned {
+		status.LastTransition = time.Now()
+		checker.transitions = append(checker.transitions, shardTransition{Endpoint: endpoint, Healthy: healthy, Message: message})
+	}
+	status.Healthy = healthy
+	status.Message = message
+}
+
+// pruneStatuses drops any tracked shard that is no longer part of the
+// current topology (resharded, sentinel failover elected new replicas,
+// nodes removed), so it stops counting towards quorum and disappears from
+// the next Statuses snapshot.
+func (checker *ShardHealthChecker) pruneStatuses(current []string) {
+	keep := make(map[string]bool, len(current))
+	for _, endpoint := range current {
+		keep[endpoint] = true
+	}
+
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	for endpoint := range checker.statuses {
+		if !keep[endpoint] {
+			delete(checker.statuses, endpoint)
+		}
+	}
+}
+
+// Ready reports whether at least quorum endpoints have been continuously
+// healthy for the configured settle window.
+func (checker *ShardHealthChecker) Ready(quorum int) bool {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	now := time.Now()
+	healthy := 0
+	for _, status := range checker.statuses {
+		if status.Healthy && now.Sub(status.LastTransition) >= checker.Settle {
+			healthy++
+		}
+	}
+
+	return healthy >= quorum
+}
+
+// Statuses returns a snapshot of every shard's current status.
+func (checker *ShardHealthChecker) Statuses() []ShardStatus {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	out := make([]ShardStatus, 0, len(checker.statuses))
+	for _, status := range checker.statuses {
+		out = append(out, *status)
+	}
+	return out
+}
+
+// DrainTransitions returns and clears every health transition observed
+// since the last call, so a live reconcile can emit an event for each one
+// using its own, current HarborCluster and event recorder.
+func (checker *ShardHealthChecker) DrainTransitions() []shardTransition {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	drained := checker.transitions
+	checker.transitions = nil
+	return drained
+}
+
+// shardEndpoints returns every "host:port" endpoint backing this connection,
+// across single-server, sentinel and cluster topologies.
+func (connect *RedisConnect) shardEndpoints() []string {
+	if connect.Schema == RedisClusterSchema {
+		return connect.Addrs
+	}
+
+	var endpoints []string
+	for _, host := range connect.Endpoints {
+		endpoints = append(endpoints, net.JoinHostPort(host, connect.Port))
+	}
+	return endpoints
+}
+
+// shardQuorum returns the minimum number of healthy shards required before
+// the cache is considered ready: a simple majority of the discovered
+// endpoints.
+func shardQuorum(total int) int {
+	if total == 0 {
+		return 0
+	}
+	return total/2 + 1
+}
+
+var (
+	shardCheckersMu sync.Mutex
+	shardCheckers   = map[string]*ShardHealthChecker{}
+)
+
+// shardHealthChecker returns the long-lived ShardHealthChecker for this
+// HarborCluster, starting its background polling goroutine the first time
+// it is requested and refreshing its connect info on every call so later
+// reconciles' endpoint, password or TLS changes reach the running poller.
+func (redis *RedisReconciler) shardHealthChecker() *ShardHealthChecker {
+	key := shardCheckerKey(redis.HarborCluster)
+
+	shardCheckersMu.Lock()
+	checker, ok := shardCheckers[key]
+	if !ok {
+		checker = NewShardHealthChecker()
+		shardCheckers[key] = checker
+	}
+	shardCheckersMu.Unlock()
+
+	checker.Refresh(redis.RedisConnect)
+
+	if !ok {
+		go checker.Start()
+	}
+
+	return checker
+}
+
+// StopShardHealthChecker stops and discards the background health checker
+// for a HarborCluster. The controller's finalizer must call this as part
+// of handling cluster deletion, so the goroutine and registry entry don't
+// leak, and so a cluster recreated with the same namespace/name starts
+// from a clean checker instead of inheriting stale shard history.
+func StopShardHealthChecker(cluster *goharborv1.HarborCluster) {
+	key := shardCheckerKey(cluster)
+
+	shardCheckersMu.Lock()
+	checker, ok := shardCheckers[key]
+	delete(shardCheckers, key)
+	shardCheckersMu.Unlock()
+
+	if ok {
+		checker.Stop()
+	}
+}
+
+func shardCheckerKey(cluster *goharborv1.HarborCluster) string {
+	return cluster.Namespace + "/" + cluster.Name
+}
+
+// Delete releases resources Readiness accumulated for this HarborCluster
+// that outlive a single reconcile, namely its shard health checker. Wire
+// this into the controller's finalizer so it runs before the CR is
+// actually removed.
+func (redis *RedisReconciler) Delete() error {
+	StopShardHealthChecker(redis.HarborCluster)
+	return nil
+}
+
+// emitShardTransitionEvent records a Kubernetes event whenever a shard flips
+// between healthy and unhealthy, so operators watching `kubectl describe`
+// see the transition instead of just a final pass/fail reconcile result.
+func (redis *RedisReconciler) emitShardTransitionEvent(endpoint string, healthy bool, message string) {
+	eventType := corev1.EventTypeNormal
+	reason := "ShardHealthy"
+	if !healthy {
+		eventType = corev1.EventTypeWarning
+		reason = "ShardUnhealthy"
+	}
+
+	redis.Recorder.Eventf(redis.HarborCluster, eventType, reason, "redis shard %s: %s", endpoint, message)
+}
+
+// syncShardConditions mirrors the checker's current view of each shard onto
+// HarborCluster.Status.Conditions, pruning entries for shards the checker
+// no longer tracks (resharded, failed over, or removed), so operators see
+// which sentinel or replica is degraded without the list growing forever.
+func (redis *RedisReconciler) syncShardConditions(checker *ShardHealthChecker) {
+	statuses := checker.Statuses()
+
+	current := make(map[goharborv1.ConditionType]bool, len(statuses))
+	for _, status := range statuses {
+		condition := goharborv1.HarborClusterCondition{
+			Type:               shardConditionType(status.Endpoint),
+			Status:             conditionStatus(status.Healthy),
+			LastTransitionTime: metav1.NewTime(status.LastTransition),
+			Message:            status.Message,
+		}
+		current[condition.Type] = true
+		setHarborClusterCondition(redis.HarborCluster, condition)
+	}
+
+	pruneStaleShardConditions(redis.HarborCluster, current)
+}
+
+func shardConditionType(endpoint string) goharborv1.ConditionType {
+	return goharborv1.ConditionType(shardConditionPrefix + endpoint)
+}
+
+func pruneStaleShardConditions(cluster *goharborv1.HarborCluster, current map[goharborv1.ConditionType]bool) {
+	kept := cluster.Status.Conditions[:0]
+	for _, condition := range cluster.Status.Conditions {
+		if !strings.HasPrefix(string(condition.Type), shardConditionPrefix) || current[condition.Type] {
+			kept = append(kept, condition)
+		}
+	}
+	cluster.Status.Conditions = kept
+}
+
+func setHarborClusterCondition(cluster *goharborv1.HarborCluster, condition goharborv1.HarborClusterCondition) {
+	for i, existing := range cluster.Status.Conditions {
+		if existing.Type == condition.Type {
+			cluster.Status.Conditions[i] = condition
+			return
+		}
+	}
+	cluster.Status.Conditions = append(cluster.Status.Conditions, condition)
+}
+
+func conditionStatus(healthy bool) corev1.ConditionStatus {
+	if healthy {
+		return corev1.ConditionTrue
+	}
+	return corev1.ConditionFalse
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	goharborv1 "github.com/goharbor/harbor-cluster-operator/api/v1"
+)
+
+func TestShardQuorum(t *testing.T) {
+	cases := map[int]int{0: 0, 1: 1, 2: 2, 3: 2, 4: 3, 5: 3}
+	for total, want := range cases {
+		if got := shardQuorum(total); got != want {
+			t.Errorf("shardQuorum(%d) = %d, want %d", total, got, want)
+		}
+	}
+}
+
+func TestShardHealthCheckerReadyRequiresQuorum(t *testing.T) {
+	checker := NewShardHealthChecker()
+	checker.Settle = 0
+
+	checker.record("n1", true, "")
+	checker.record("n2", true, "")
+
+	if !checker.Ready(2) {
+		t.Fatal("Ready(2) = false, want true once settle window has elapsed")
+	}
+	if checker.Ready(3) {
+		t.Fatal("Ready(3) = true, want false: only 2 shards are known")
+	}
+}
+
+func TestShardHealthCheckerReadyWaitsForSettleWindow(t *testing.T) {
+	checker := NewShardHealthChecker()
+	checker.Settle = time.Hour
+
+	checker.record("n1", true, "")
+
+	if checker.Ready(1) {
+		t.Fatal("Ready(1) = true, want false: shard has not settled yet")
+	}
+}
+
+func TestShardHealthCheckerRecordTracksTransitions(t *testing.T) {
+	checker := NewShardHealthChecker()
+
+	checker.record("n1", true, "")
+	checker.record("n1", true, "") // no-op, same state
+	checker.record("n1", false, "connection refused")
+
+	transitions := checker.DrainTransitions()
+	if len(transitions) != 2 {
+		t.Fatalf("DrainTransitions() = %d transitions, want 2", len(transitions))
+	}
+	if transitions[1].Healthy {
+		t.Error("second transition should record the shard going unhealthy")
+	}
+
+	if got := checker.DrainTransitions(); len(got) != 0 {
+		t.Errorf("DrainTransitions() after drain = %v, want empty", got)
+	}
+}
+
+func TestShardHealthCheckerPruneStatuses(t *testing.T) {
+	checker := NewShardHealthChecker()
+	checker.record("n1", true, "")
+	checker.record("n2", true, "")
+
+	checker.pruneStatuses([]string{"n1"})
+
+	statuses := checker.Statuses()
+	if len(statuses) != 1 || statuses[0].Endpoint != "n1" {
+		t.Fatalf("Statuses() = %v, want only n1 to remain", statuses)
+	}
+}
+
+func TestPruneStaleShardConditions(t *testing.T) {
+	cluster := &goharborv1.HarborCluster{}
+	cluster.Status.Conditions = []goharborv1.HarborClusterCondition{
+		{Type: shardConditionType("n1")},
+		{Type: shardConditionType("n2")},
+		{Type: "SomeOtherCondition"},
+	}
+
+	pruneStaleShardConditions(cluster, map[goharborv1.ConditionType]bool{shardConditionType("n1"): true})
+
+	if len(cluster.Status.Conditions) != 2 {
+		t.Fatalf("Conditions = %v, want n1's shard condition and the unrelated condition to remain", cluster.Status.Conditions)
+	}
+}
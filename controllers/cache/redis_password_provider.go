@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	goharborv1 "github.com/goharbor/harbor-cluster-operator/api/v1"
+	vaultapi "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// serviceAccountTokenPath is where Kubernetes projects the pod's service
+// account token, used to authenticate the Vault kubernetes auth method.
+const serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+func readServiceAccountToken() (string, error) {
+	token, err := ioutil.ReadFile(serviceAccountTokenPath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(token), nil
+}
+
+// redisPasswordVersionAnnotation records the last password version a
+// reconcile observed from the configured PasswordProvider, letting
+// resolveRedisPassword tell "still the same password" apart from "the
+// upstream store rotated it" without rewriting the HarborCluster on every
+// reconcile. Component secrets are re-issued by DeployComponentSecret
+// whenever their generated data differs, independent of this annotation.
+const redisPasswordVersionAnnotation = "harbor.goharbor.io/redis-password-version"
+
+// PasswordProvider resolves the Redis password from whichever store an
+// operator has configured. Version is an opaque, provider-specific string
+// that changes whenever the underlying password does, so callers can
+// detect rotation without comparing secret values directly.
+type PasswordProvider interface {
+	GetPassword() (password string, version string, err error)
+}
+
+// KubernetesSecretProvider is the default PasswordProvider and preserves
+// the operator's original behavior: it reads the password straight out of
+// a Kubernetes Secret. The Secret's resourceVersion doubles as the password
+// version, since editing the Secret is the only way to rotate it.
+type KubernetesSecretProvider struct {
+	redis      *RedisReconciler
+	secretName string
+}
+
+// GetPassword implements PasswordProvider.
+func (p *KubernetesSecretProvider) GetPassword() (string, string, error) {
+	secret := &corev1.Secret{}
+	if err := p.redis.Client.Get(types.NamespacedName{Name: p.secretName, Namespace: p.redis.HarborCluster.Namespace}, secret); err != nil {
+		return "", "", err
+	}
+
+	return string(secret.Data["redis-password"]), secret.ResourceVersion, nil
+}
+
+// HashicorpVaultProvider resolves the password from a KV v2 secret in
+// Vault, addressed by a goharborv1.VaultRef on the CRD.
+type HashicorpVaultProvider struct {
+	ref goharborv1.VaultRef
+}
+
+// GetPassword implements PasswordProvider.
+func (p *HashicorpVaultProvider) GetPassword() (string, string, error) {
+	client, err := vaultapi.NewClient(&vaultapi.Config{Address: p.ref.Address})
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := authenticateVault(client, p.ref); err != nil {
+		return "", "", err
+	}
+
+	secret, err := client.Logical().Read(p.ref.Path)
+	if err != nil {
+		return "", "", err
+	}
+	if secret == nil {
+		return "", "", fmt.Errorf("vault path %s returned no secret", p.ref.Path)
+	}
+
+	data, _ := secret.Data["data"].(map[string]interface{})
+	password, _ := data["password"].(string)
+
+	return password, fmt.Sprintf("%v", secret.Data["metadata"]), nil
+}
+
+// authenticateVault logs the Vault client in using ref.AuthMethod. Only
+// "kubernetes" auth, with ref.RoleName as the bound role, is supported; any
+// other method returns an error.
+func authenticateVault(client *vaultapi.Client, ref goharborv1.VaultRef) error {
+	switch ref.AuthMethod {
+	case "kubernetes":
+		jwt, err := readServiceAccountToken()
+		if err != nil {
+			return err
+		}
+
+		secret, err := client.Logical().Write("auth/kubernetes/login", map[string]interface{}{
+			"role": ref.RoleName,
+			"jwt":  jwt,
+		})
+		if err != nil {
+			return err
+		}
+
+		client.SetToken(secret.Auth.ClientToken)
+
+		return nil
+	default:
+		return fmt.Errorf("unsupported vault auth method %q", ref.AuthMethod)
+	}
+}
+
+// AWSSecretsManagerProvider resolves the password from AWS Secrets Manager,
+// authenticating via IRSA (the pod's mounted web identity token) rather
+// than long-lived static credentials.
+type AWSSecretsManagerProvider struct {
+	secretID string
+	region   string
+}
+
+// GetPassword implements PasswordProvider.
+func (p *AWSSecretsManagerProvider) GetPassword() (string, string, error) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(p.region)})
+	if err != nil {
+		return "", "", err
+	}
+
+	out, err := secretsmanager.New(sess).GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(p.secretID),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return aws.StringValue(out.SecretString), aws.StringValue(out.VersionId), nil
+}
+
+// passwordProvider builds the PasswordProvider configured for spec,
+// defaulting to the operator's original Kubernetes Secret behavior when no
+// external store is set.
+func (redis *RedisReconciler) passwordProvider(spec *goharborv1.RedisSpec) PasswordProvider {
+	switch {
+	case spec.Vault != nil:
+		return &HashicorpVaultProvider{ref: *spec.Vault}
+	case spec.AWSSecretsManager != nil:
+		return &AWSSecretsManagerProvider{
+			secretID: spec.AWSSecretsManager.SecretId,
+			region:   spec.AWSSecretsManager.Region,
+		}
+	default:
+		return &KubernetesSecretProvider{redis: redis, secretName: spec.SecretName}
+	}
+}
+
+// resolveRedisPassword fetches the password from spec's configured
+// PasswordProvider and, when the reported version differs from the one
+// last recorded, stores the new version on HarborCluster.
+func (redis *RedisReconciler) resolveRedisPassword(spec *goharborv1.RedisSpec) (string, error) {
+	provider := redis.passwordProvider(spec)
+
+	password, version, err := provider.GetPassword()
+	if err != nil {
+		return "", err
+	}
+
+	annotations := redis.HarborCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if annotations[redisPasswordVersionAnnotation] != version {
+		annotations[redisPasswordVersionAnnotation] = version
+		redis.HarborCluster.SetAnnotations(annotations)
+
+		// Persist the rotation immediately: HarborCluster is refetched on
+		// every reconcile, so an in-memory-only annotation would be
+		// discarded before the next one ever saw it.
+		if err := redis.Client.Update(redis.HarborCluster); err != nil {
+			return "", err
+		}
+	}
+
+	return password, nil
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-cluster-operator/api/v1"
+)
+
+func TestPasswordProviderSelection(t *testing.T) {
+	redis := &RedisReconciler{}
+
+	cases := []struct {
+		name string
+		spec *goharborv1.RedisSpec
+		want interface{}
+	}{
+		{
+			name: "defaults to kubernetes secret",
+			spec: &goharborv1.RedisSpec{SecretName: "redis-secret"},
+			want: &KubernetesSecretProvider{},
+		},
+		{
+			name: "vault takes precedence when set",
+			spec: &goharborv1.RedisSpec{
+				SecretName: "redis-secret",
+				Vault:      &goharborv1.VaultRef{Address: "https://vault:8200", Path: "secret/data/redis"},
+			},
+			want: &HashicorpVaultProvider{},
+		},
+		{
+			name: "aws secrets manager when set",
+			spec: &goharborv1.RedisSpec{
+				AWSSecretsManager: &goharborv1.AWSSecretsManagerRef{SecretId: "redis-password", Region: "us-east-1"},
+			},
+			want: &AWSSecretsManagerProvider{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := redis.passwordProvider(tc.spec)
+
+			switch tc.want.(type) {
+			case *KubernetesSecretProvider:
+				if _, ok := got.(*KubernetesSecretProvider); !ok {
+					t.Errorf("passwordProvider() = %T, want *KubernetesSecretProvider", got)
+				}
+			case *HashicorpVaultProvider:
+				if _, ok := got.(*HashicorpVaultProvider); !ok {
+					t.Errorf("passwordProvider() = %T, want *HashicorpVaultProvider", got)
+				}
+			case *AWSSecretsManagerProvider:
+				if _, ok := got.(*AWSSecretsManagerProvider); !ok {
+					t.Errorf("passwordProvider() = %T, want *AWSSecretsManagerProvider", got)
+				}
+			}
+		})
+	}
+}
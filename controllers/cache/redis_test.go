@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"testing"
+
+	goharborv1 "github.com/goharbor/harbor-cluster-operator/api/v1"
+)
+
+func TestGenRedisConnURL(t *testing.T) {
+	cases := []struct {
+		name    string
+		connect *RedisConnect
+		want    string
+	}{
+		{
+			name: "single server",
+			connect: &RedisConnect{
+				Endpoints: []string{"redis.example.com"},
+				Port:      "6379",
+				Schema:    RedisServerSchema,
+			},
+			want: "redis://redis.example.com:6379",
+		},
+		{
+			name: "sentinel",
+			connect: &RedisConnect{
+				Endpoints: []string{"s1", "s2"},
+				Port:      "26379",
+				GroupName: "mymaster",
+				Schema:    RedisSentinelSchema,
+			},
+			want: "redis+sentinel://s1:26379,s2:26379?master=mymaster",
+		},
+		{
+			name: "cluster",
+			connect: &RedisConnect{
+				Addrs:  []string{"n1:6379", "n2:6380"},
+				Schema: RedisClusterSchema,
+			},
+			want: "redis+cluster://n1:6379,n2:6380",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.connect.GenRedisConnURL(); got != tc.want {
+				t.Errorf("GenRedisConnURL() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetExternalRedisClusterAddrs(t *testing.T) {
+	spec := &goharborv1.RedisSpec{
+		Hosts: []goharborv1.RedisHostSpec{
+			{Host: "n1", Port: "6379"},
+			{Host: "n2", Port: "6380"},
+		},
+	}
+
+	got := GetExternalRedisClusterAddrs(spec)
+	want := []string{"n1:6379", "n2:6380"}
+
+	if len(got) != len(want) {
+		t.Fatalf("GetExternalRedisClusterAddrs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("GetExternalRedisClusterAddrs() = %v, want %v", got, want)
+		}
+	}
+}
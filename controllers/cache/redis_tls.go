@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	goharborv1 "github.com/goharbor/harbor-cluster-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RedisTLSHandshakeError is returned via cacheNotReadyStatus when the Redis
+// connection fails during the TLS handshake rather than a plain network or
+// authentication error, so operators can tell a cert/CA problem apart from
+// a reachability one.
+const RedisTLSHandshakeError = "RedisTLSHandshakeError"
+
+// buildTLSConfig loads the CA certificate and, when present, the client
+// certificate/key referenced by spec out of the Kubernetes Secret it points
+// at, and turns them into a *tls.Config ready to hand to a go-redis client.
+// A nil spec yields a nil config, meaning "plain TCP".
+func (redis *RedisReconciler) buildTLSConfig(spec *goharborv1.RedisTLSSpec) (*tls.Config, error) {
+	if spec == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := redis.Client.Get(types.NamespacedName{Name: spec.SecretName, Namespace: redis.HarborCluster.Namespace}, secret); err != nil {
+		return nil, err
+	}
+
+	caCert, ok := secret.Data["ca.crt"]
+	if !ok {
+		return nil, fmt.Errorf("secret %s is missing ca.crt", spec.SecretName)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("secret %s does not contain a valid ca.crt", spec.SecretName)
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:            pool,
+		InsecureSkipVerify: spec.InsecureSkipVerify,
+	}
+
+	certPEM, hasCert := secret.Data["tls.crt"]
+	keyPEM, hasKey := secret.Data["tls.key"]
+	if hasCert && hasKey {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s has an invalid tls.crt/tls.key pair: %w", spec.SecretName, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// isTLSHandshakeError reports whether err originated from the TLS layer
+// rather than from Redis auth or plain connectivity, so Readiness can
+// surface a distinct, more actionable error code for it.
+func isTLSHandshakeError(err error) bool {
+	var recordHeaderErr tls.RecordHeaderError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+
+	switch {
+	case errors.As(err, &recordHeaderErr),
+		errors.As(err, &unknownAuthorityErr),
+		errors.As(err, &hostnameErr):
+		return true
+	default:
+		return strings.Contains(err.Error(), "tls:")
+	}
+}
+
+// upgradeRedisURLScheme switches a generated redis://... URL to the rediss://
+// scheme used once TLS is enabled, preserving any "+topology" suffix such as
+// redis+cluster:// or redis+sentinel://.
+func upgradeRedisURLScheme(url string) string {
+	if strings.HasPrefix(url, "rediss") {
+		return url
+	}
+	return "rediss" + strings.TrimPrefix(url, "redis")
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"testing"
+)
+
+func TestIsTLSHandshakeError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"record header error", tls.RecordHeaderError{}, true},
+		{"unknown authority", x509.UnknownAuthorityError{}, true},
+		{"plain connection refused", errors.New("dial tcp: connection refused"), false},
+		{"wrapped tls message", errors.New("read tcp: tls: bad certificate"), true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isTLSHandshakeError(tc.err); got != tc.want {
+				t.Errorf("isTLSHandshakeError(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpgradeRedisURLScheme(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"redis://host:6379", "rediss://host:6379"},
+		{"redis+sentinel://host:26379", "rediss+sentinel://host:26379"},
+		{"redis+cluster://host:6379", "rediss+cluster://host:6379"},
+		{"rediss://host:6379", "rediss://host:6379"},
+	}
+
+	for _, tc := range cases {
+		if got := upgradeRedisURLScheme(tc.in); got != tc.want {
+			t.Errorf("upgradeRedisURLScheme(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}